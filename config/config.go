@@ -0,0 +1,30 @@
+package config
+
+// Config is the contents of a .gitkick.yaml file.
+type Config struct {
+	CustomCommands []CustomCommand `yaml:"customCommands"`
+	Squash         SquashConfig    `yaml:"squash"`
+}
+
+// SquashConfig holds defaults for the `kk squash` flow.
+type SquashConfig struct {
+	Autostash bool `yaml:"autostash"`
+}
+
+// CustomCommand is a single user-defined command composed of gitkick
+// primitives (SubCommands) and/or an arbitrary git/shell invocation (Command).
+type CustomCommand struct {
+	Name        string   `yaml:"name"`
+	Prompts     []Prompt `yaml:"prompts,omitempty"`
+	Command     string   `yaml:"command,omitempty"`
+	SubCommands []string `yaml:"subCommands,omitempty"`
+}
+
+// Prompt names one value, interpolated into Command as {{.Key}}. The value is
+// read from a --<Key> flag on the command line, falling back to Default; the
+// user is not asked interactively and Title is not shown.
+type Prompt struct {
+	Key     string `yaml:"key"`
+	Title   string `yaml:"title,omitempty"`
+	Default string `yaml:"default,omitempty"`
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const fileName = ".gitkick.yaml"
+
+// Load reads a repo-local .gitkick.yaml if present, falling back to one in
+// the user's home directory. A missing file is not an error: Load returns an
+// empty Config so gitkick still runs with just its built-in commands.
+func Load() (*Config, error) {
+	for _, path := range candidatePaths() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		var cfg Config
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+		return &cfg, nil
+	}
+
+	return &Config{}, nil
+}
+
+func candidatePaths() []string {
+	var paths []string
+	if cwd, err := os.Getwd(); err == nil {
+		paths = append(paths, filepath.Join(cwd, fileName))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, fileName))
+	}
+	return paths
+}
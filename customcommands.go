@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"text/template"
+
+	"github.com/makehlv/gitkick/config"
+	"github.com/makehlv/gitkick/oscommands"
+	"github.com/makehlv/gitkick/str"
+)
+
+// CustomCommandRunner dispatches config.CustomCommand entries: it chains any
+// built-in SubCommands through CodeFlowManageService, then - if Command is
+// set - interpolates it and runs it as a real argv, never through a shell.
+type CustomCommandRunner struct {
+	logger  *slog.Logger
+	manage  *CodeFlowManageService
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+}
+
+func NewCustomCommandRunner(logger *slog.Logger, manage *CodeFlowManageService, runner oscommands.Runner) *CustomCommandRunner {
+	return &CustomCommandRunner{
+		logger:  logger,
+		manage:  manage,
+		runner:  runner,
+		builder: oscommands.NewCmdObjBuilder(),
+	}
+}
+
+// Run executes a custom command. vars holds answers to cmd.Prompts, keyed by
+// Prompt.Key, interpolated into cmd.Command as {{.Key}}.
+func (r *CustomCommandRunner) Run(cmd config.CustomCommand, vars map[string]string) error {
+	for _, sub := range cmd.SubCommands {
+		if err := r.runBuiltin(sub); err != nil {
+			return fmt.Errorf("sub-command %s failed: %w", sub, err)
+		}
+	}
+
+	if cmd.Command == "" {
+		return nil
+	}
+
+	rendered, err := r.render(cmd.Command, vars)
+	if err != nil {
+		return fmt.Errorf("failed to render command %q: %w", cmd.Name, err)
+	}
+
+	argv, err := str.ToArgv(rendered)
+	if err != nil {
+		return fmt.Errorf("failed to parse command %q: %w", cmd.Name, err)
+	}
+	if len(argv) == 0 {
+		return nil
+	}
+
+	return r.runner.RunWithStreaming(r.builder.New(argv...), func(line string) {
+		r.logger.Info(cmd.Name, "output", line)
+	})
+}
+
+func (r *CustomCommandRunner) runBuiltin(name string) error {
+	switch name {
+	case "squash":
+		return r.manage.Squash("develop", "", SquashModeSoft, false)
+	case "clean":
+		return r.manage.CleanFallbackBranches(false)
+	case "commit":
+		return r.manage.Commit()
+	case "push":
+		return r.manage.Push()
+	default:
+		return fmt.Errorf("unknown built-in command: %s", name)
+	}
+}
+
+func (r *CustomCommandRunner) render(tmpl string, vars map[string]string) (string, error) {
+	t, err := template.New("command").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// mergedHelp lists built-in commands alongside any user-defined ones from cfg.
+func mergedHelp(cfg *config.Config) string {
+	help := "usage: kk <command> [flags]\n\nbuilt-in commands:\n  squash\n  rebase\n  clean\n  commit\n  push"
+	if len(cfg.CustomCommands) == 0 {
+		return help
+	}
+
+	help += "\n\ncustom commands:"
+	for _, cmd := range cfg.CustomCommands {
+		help += fmt.Sprintf("\n  %s", cmd.Name)
+	}
+	return help
+}
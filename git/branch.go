@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// BranchCommands groups git operations that read or mutate branch refs.
+type BranchCommands struct {
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+}
+
+func NewBranchCommands(runner oscommands.Runner, builder *oscommands.CmdObjBuilder) *BranchCommands {
+	return &BranchCommands{runner: runner, builder: builder}
+}
+
+func (c *BranchCommands) GetCurrentBranch() (Ref, error) {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "branch", "--show-current"))
+	if err != nil {
+		return Ref{}, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return NewLocalBranchRef(strings.TrimSpace(out)), nil
+}
+
+func (c *BranchCommands) NewBranch(ref Ref) error {
+	if out, err := c.runner.RunWithOutput(c.builder.New("git", "branch", ref.Name)); err != nil {
+		return fmt.Errorf("failed to create branch %s: %s", ref.Name, out)
+	}
+	return nil
+}
+
+func (c *BranchCommands) SwitchToBranch(ref Ref) error {
+	if out, err := c.runner.RunWithOutput(c.builder.New("git", "switch", ref.Name)); err != nil {
+		return fmt.Errorf("failed to switch branch %s: %s", ref.Name, out)
+	}
+	return nil
+}
+
+func (c *BranchCommands) DeleteLocalBranch(ref Ref) error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "branch", "-D", ref.Name))
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s err %s", ref.Name, out)
+	}
+	return nil
+}
+
+func (c *BranchCommands) ListBranchesWithPrefix(prefix string) ([]Ref, error) {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "branch", "--list", prefix+"*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(out)
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	var refs []Ref
+	for _, line := range strings.Split(trimmed, "\n") {
+		if ref := ParseRef(line); ref.Name != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+func (c *BranchCommands) GetCommitsDiffCount(target Ref) (int, error) {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "cherry", "-v", target.Name))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count diff for target branch %s: %w", target.Name, err)
+	}
+
+	trimmed := strings.TrimSpace(out)
+	if trimmed == "" {
+		return 0, nil
+	}
+
+	return len(strings.Split(trimmed, "\n")), nil
+}
@@ -0,0 +1,37 @@
+package git
+
+import "github.com/makehlv/gitkick/oscommands"
+
+// GitCommand aggregates the narrower command groups into a single handle,
+// mirroring the one git binding the CLI actually needs to hold onto.
+type GitCommand struct {
+	*BranchCommands
+	*CommitCommands
+	*WorkingTreeCommands
+	*RemoteCommands
+	*RebaseCommands
+
+	// Stash is a named field, not embedded: StashCommands.Push would
+	// otherwise collide with the promoted RemoteCommands.Push.
+	Stash *StashCommands
+}
+
+// NewGitCommand wires up a GitCommand backed by a real Runner that shells out
+// to the git binary.
+func NewGitCommand() *GitCommand {
+	return NewGitCommandWithRunner(oscommands.NewExecRunner())
+}
+
+// NewGitCommandWithRunner wires up a GitCommand on top of the given Runner,
+// so tests can swap in a FakeRunner.
+func NewGitCommandWithRunner(runner oscommands.Runner) *GitCommand {
+	builder := oscommands.NewCmdObjBuilder()
+	return &GitCommand{
+		BranchCommands:      NewBranchCommands(runner, builder),
+		CommitCommands:      NewCommitCommands(runner, builder),
+		WorkingTreeCommands: NewWorkingTreeCommands(runner, builder),
+		RemoteCommands:      NewRemoteCommands(runner, builder),
+		RebaseCommands:      NewRebaseCommands(runner, builder),
+		Stash:               NewStashCommands(runner, builder),
+	}
+}
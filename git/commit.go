@@ -0,0 +1,71 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// CommitInfo is one commit as listed by ListCommitsSince.
+type CommitInfo struct {
+	SHA     string
+	Subject string
+}
+
+// CommitCommands groups git operations that create or rewrite commits.
+type CommitCommands struct {
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+}
+
+func NewCommitCommands(runner oscommands.Runner, builder *oscommands.CmdObjBuilder) *CommitCommands {
+	return &CommitCommands{runner: runner, builder: builder}
+}
+
+func (c *CommitCommands) Commit(message string) error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "commit", "-m", message))
+	if err != nil {
+		return fmt.Errorf("failed to commit %s", out)
+	}
+	return nil
+}
+
+func (c *CommitCommands) ResetSoft(commitsFromHead int) error {
+	commitsToReset := fmt.Sprintf("HEAD~%d", commitsFromHead)
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "reset", "--soft", commitsToReset))
+	if err != nil {
+		return fmt.Errorf("failed to reset softly %s", out)
+	}
+	return nil
+}
+
+func (c *CommitCommands) GenerateTimestamp() string {
+	return time.Now().Format("2006-01-02-15-04-05")
+}
+
+// ListCommitsSince lists the commits on HEAD that aren't on baseRef, oldest
+// first, so a caller can turn them into a rebase todo list.
+func (c *CommitCommands) ListCommitsSince(baseRef string) ([]CommitInfo, error) {
+	rangeSpec := fmt.Sprintf("%s..HEAD", baseRef)
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "log", "--reverse", "--format=%h\t%s", rangeSpec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits since %s: %s", baseRef, out)
+	}
+
+	trimmed := strings.TrimSpace(out)
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	var commits []CommitInfo
+	for _, line := range strings.Split(trimmed, "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		commits = append(commits, CommitInfo{SHA: parts[0], Subject: parts[1]})
+	}
+	return commits, nil
+}
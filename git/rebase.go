@@ -0,0 +1,111 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// TodoAction is one rebase todo verb gitkick drives `git rebase -i` with.
+type TodoAction string
+
+const (
+	TodoActionPick   TodoAction = "pick"
+	TodoActionSquash TodoAction = "squash"
+	TodoActionFixup  TodoAction = "fixup"
+)
+
+// TodoEntry is one line of a rebase todo file.
+type TodoEntry struct {
+	Action  TodoAction
+	SHA     string
+	Subject string
+}
+
+// RebaseCommands drives `git rebase -i` against a fixed, pre-written todo
+// list, so a squash can be performed without losing author/date metadata.
+type RebaseCommands struct {
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+	repoDir string
+}
+
+func NewRebaseCommands(runner oscommands.Runner, builder *oscommands.CmdObjBuilder) *RebaseCommands {
+	return &RebaseCommands{runner: runner, builder: builder, repoDir: "."}
+}
+
+// InteractiveRebase writes todos to a tempfile and runs `git rebase -i
+// baseRef` with GIT_SEQUENCE_EDITOR pointed at a command that copies that
+// tempfile over the rebase-todo git generates, so the todo list is exactly
+// what the caller asked for rather than anything a user/editor would produce.
+// GIT_EDITOR is forced to "true" as well, since every squash/fixup todo line
+// also opens a commit-message editor; without it git blocks waiting on an
+// editor (or fails outright with no TTY) instead of accepting the default
+// combined message.
+func (c *RebaseCommands) InteractiveRebase(baseRef string, todos []TodoEntry) error {
+	todoFile, err := writeTodoFile(todos)
+	if err != nil {
+		return fmt.Errorf("failed to write rebase todo: %w", err)
+	}
+	defer os.Remove(todoFile)
+
+	cmd := c.builder.New("git", "rebase", "-i", baseRef).SetDir(c.repoDir)
+	cmd.AddEnvVars("GIT_SEQUENCE_EDITOR=cp "+todoFile, "GIT_EDITOR=true")
+
+	out, err := c.runner.RunWithOutput(cmd)
+	if err != nil {
+		if c.InProgress() {
+			return fmt.Errorf("rebase stopped with conflicts, resolve them then run `kk rebase --continue` (or `kk rebase --abort`): %s", out)
+		}
+		return fmt.Errorf("failed to rebase onto %s: %s", baseRef, out)
+	}
+	return nil
+}
+
+// InProgress reports whether a rebase is currently in flight in this repo.
+func (c *RebaseCommands) InProgress() bool {
+	if _, err := os.Stat(filepath.Join(c.repoDir, ".git", "rebase-merge")); err == nil {
+		return true
+	}
+	if _, err := os.Stat(filepath.Join(c.repoDir, ".git", "rebase-apply")); err == nil {
+		return true
+	}
+	return false
+}
+
+func (c *RebaseCommands) Abort() error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "rebase", "--abort").SetDir(c.repoDir))
+	if err != nil {
+		return fmt.Errorf("failed to abort rebase: %s", out)
+	}
+	return nil
+}
+
+func (c *RebaseCommands) Continue() error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "rebase", "--continue").SetDir(c.repoDir))
+	if err != nil {
+		return fmt.Errorf("failed to continue rebase: %s", out)
+	}
+	return nil
+}
+
+func writeTodoFile(todos []TodoEntry) (string, error) {
+	f, err := os.CreateTemp("", "kk-rebase-todo-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for _, t := range todos {
+		fmt.Fprintf(&b, "%s %s %s\n", t.Action, t.SHA, t.Subject)
+	}
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// runGit runs git against dir and fails the test on error, for building the
+// fixture repo this test rebases.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=kk", "GIT_AUTHOR_EMAIL=kk@example.com",
+		"GIT_COMMITTER_NAME=kk", "GIT_COMMITTER_EMAIL=kk@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %s: %s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// TestInteractiveRebaseSquashesWithoutAnEditor drives a real 2-commit squash
+// through InteractiveRebase against a real repo, to catch the commit-message
+// editor that git also opens for every squash/fixup todo line (on top of the
+// todo list editor GIT_SEQUENCE_EDITOR replaces).
+func TestInteractiveRebaseSquashesWithoutAnEditor(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "base")
+	runGit(t, dir, "config", "user.email", "kk@example.com")
+	runGit(t, dir, "config", "user.name", "kk")
+	runGit(t, dir, "commit", "--allow-empty", "-q", "-m", "base commit")
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-q", "-m", "first commit")
+
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "b.txt")
+	runGit(t, dir, "commit", "-q", "-m", "second commit")
+
+	firstSHA := strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD~1"))
+	secondSHA := strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD"))
+
+	c := &RebaseCommands{runner: oscommands.NewExecRunner(), builder: oscommands.NewCmdObjBuilder(), repoDir: dir}
+	todos := []TodoEntry{
+		{Action: TodoActionPick, SHA: firstSHA, Subject: "first commit"},
+		{Action: TodoActionSquash, SHA: secondSHA, Subject: "second commit"},
+	}
+
+	if err := c.InteractiveRebase("base", todos); err != nil {
+		t.Fatalf("InteractiveRebase() error = %v", err)
+	}
+
+	if c.InProgress() {
+		t.Fatal("expected the rebase to have finished, not stopped mid-flight")
+	}
+
+	log := runGit(t, dir, "log", "--format=%s", "base..HEAD")
+	subjects := strings.Split(strings.TrimSpace(log), "\n")
+	if len(subjects) != 1 {
+		t.Fatalf("expected exactly one commit after squashing, got %v", subjects)
+	}
+	if strings.Contains(subjects[0], "This is a combination of") {
+		t.Fatalf("expected a resolved commit message, got the unedited template: %q", subjects[0])
+	}
+}
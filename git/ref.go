@@ -0,0 +1,76 @@
+package git
+
+import "strings"
+
+// RefType classifies a Ref by the ref namespace it lives in, so callers can
+// no longer confuse a local branch with a remote-tracking branch or tag.
+type RefType string
+
+const (
+	RefTypeLocalBranch  RefType = "localBranch"
+	RefTypeRemoteBranch RefType = "remoteBranch"
+	RefTypeTag          RefType = "tag"
+	RefTypeHEAD         RefType = "head"
+	RefTypeOther        RefType = "other"
+)
+
+// Ref is a single git ref: a name plus the namespace it belongs to.
+type Ref struct {
+	Name string
+	Type RefType
+}
+
+func NewLocalBranchRef(name string) Ref {
+	return Ref{Name: name, Type: RefTypeLocalBranch}
+}
+
+func NewRemoteBranchRef(name string) Ref {
+	return Ref{Name: name, Type: RefTypeRemoteBranch}
+}
+
+// Prefix returns the refs/ namespace this Ref's type lives under. HEAD and
+// RefTypeOther have no fixed namespace, so Prefix returns "".
+func (r Ref) Prefix() string {
+	switch r.Type {
+	case RefTypeLocalBranch:
+		return "refs/heads"
+	case RefTypeRemoteBranch:
+		return "refs/remotes"
+	case RefTypeTag:
+		return "refs/tags"
+	default:
+		return ""
+	}
+}
+
+// FullName renders the fully-qualified ref, e.g. refs/heads/main.
+func (r Ref) FullName() string {
+	if prefix := r.Prefix(); prefix != "" {
+		return prefix + "/" + r.Name
+	}
+	return r.Name
+}
+
+// ParseRef classifies a raw ref string (as printed by porcelain-ish commands
+// like `git branch --list` or `git for-each-ref`) by inspecting its prefix.
+func ParseRef(raw string) Ref {
+	name := strings.TrimSpace(strings.TrimPrefix(raw, "*"))
+	name = strings.TrimSpace(name)
+
+	switch {
+	case name == "HEAD":
+		return Ref{Name: name, Type: RefTypeHEAD}
+	case strings.HasPrefix(name, "refs/heads/"):
+		return NewLocalBranchRef(strings.TrimPrefix(name, "refs/heads/"))
+	case strings.HasPrefix(name, "refs/remotes/"):
+		return NewRemoteBranchRef(strings.TrimPrefix(name, "refs/remotes/"))
+	case strings.HasPrefix(name, "remotes/"):
+		return NewRemoteBranchRef(strings.TrimPrefix(name, "remotes/"))
+	case strings.HasPrefix(name, "refs/tags/"):
+		return Ref{Name: strings.TrimPrefix(name, "refs/tags/"), Type: RefTypeTag}
+	case name == "":
+		return Ref{Type: RefTypeOther}
+	default:
+		return NewLocalBranchRef(name)
+	}
+}
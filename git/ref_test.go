@@ -0,0 +1,47 @@
+package git
+
+import "testing"
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Ref
+	}{
+		{name: "plain local branch", raw: "main", want: Ref{Name: "main", Type: RefTypeLocalBranch}},
+		{name: "current branch marker", raw: "* feature/foo", want: Ref{Name: "feature/foo", Type: RefTypeLocalBranch}},
+		{name: "fully qualified local branch", raw: "refs/heads/main", want: Ref{Name: "main", Type: RefTypeLocalBranch}},
+		{name: "fully qualified remote branch", raw: "refs/remotes/origin/main", want: Ref{Name: "origin/main", Type: RefTypeRemoteBranch}},
+		{name: "short-form remote branch", raw: "remotes/origin/main", want: Ref{Name: "origin/main", Type: RefTypeRemoteBranch}},
+		{name: "tag", raw: "refs/tags/v1.0.0", want: Ref{Name: "v1.0.0", Type: RefTypeTag}},
+		{name: "head", raw: "HEAD", want: Ref{Name: "HEAD", Type: RefTypeHEAD}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRef(tt.raw); got != tt.want {
+				t.Fatalf("ParseRef(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefFullName(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  Ref
+		want string
+	}{
+		{name: "local branch", ref: NewLocalBranchRef("main"), want: "refs/heads/main"},
+		{name: "remote branch", ref: NewRemoteBranchRef("origin/main"), want: "refs/remotes/origin/main"},
+		{name: "head has no prefix", ref: Ref{Name: "HEAD", Type: RefTypeHEAD}, want: "HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref.FullName(); got != tt.want {
+				t.Fatalf("FullName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
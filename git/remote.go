@@ -0,0 +1,40 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// RemoteCommands groups git operations that talk to a remote.
+type RemoteCommands struct {
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+}
+
+func NewRemoteCommands(runner oscommands.Runner, builder *oscommands.CmdObjBuilder) *RemoteCommands {
+	return &RemoteCommands{runner: runner, builder: builder}
+}
+
+func (c *RemoteCommands) Push(branchName string) error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "push", "--set-upstream", "origin", branchName))
+	if err != nil {
+		return fmt.Errorf("failed to push %s", out)
+	}
+	return nil
+}
+
+// DeleteRemoteBranch deletes ref's matching branch on origin. A ref that was
+// never pushed (origin has no matching branch) is treated as already clean
+// rather than an error.
+func (c *RemoteCommands) DeleteRemoteBranch(ref Ref) error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "push", "origin", "--delete", ref.Name))
+	if err != nil {
+		if strings.Contains(out, "remote ref does not exist") {
+			return nil
+		}
+		return fmt.Errorf("failed to delete remote branch %s: %s", ref.Name, out)
+	}
+	return nil
+}
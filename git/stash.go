@@ -0,0 +1,76 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// StashEntry is one entry from `git stash list`.
+type StashEntry struct {
+	Index   int
+	Message string
+}
+
+// StashCommands groups operations against the stash. Its Push is kept off
+// GitCommand's anonymous embedding (see GitCommand.Stash) since its name
+// would otherwise collide with RemoteCommands.Push.
+type StashCommands struct {
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+}
+
+func NewStashCommands(runner oscommands.Runner, builder *oscommands.CmdObjBuilder) *StashCommands {
+	return &StashCommands{runner: runner, builder: builder}
+}
+
+// Push stashes tracked and untracked changes under message. It returns
+// created=false (with no error) when there was nothing to stash.
+func (c *StashCommands) Push(message string) (created bool, err error) {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "stash", "push", "--include-untracked", "-m", message))
+	if err != nil {
+		return false, fmt.Errorf("failed to stash changes: %s", out)
+	}
+	if strings.Contains(out, "No local changes to save") {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Pop applies and drops the most recent stash entry. A conflict surfaced by
+// `git stash pop` is reported distinctly so callers can tell the user the
+// stash entry is still kept and needs manual conflict resolution and dropping.
+func (c *StashCommands) Pop() error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "stash", "pop"))
+	if err != nil {
+		if strings.Contains(out, "CONFLICT") {
+			return fmt.Errorf("stash pop caused a conflict, resolve it and drop the stash manually: %s", out)
+		}
+		return fmt.Errorf("failed to pop stash: %s", out)
+	}
+	return nil
+}
+
+func (c *StashCommands) List() ([]StashEntry, error) {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "stash", "list"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stash entries: %s", out)
+	}
+
+	trimmed := strings.TrimSpace(out)
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	var entries []StashEntry
+	for i, line := range strings.Split(trimmed, "\n") {
+		parts := strings.SplitN(line, ": ", 2)
+		message := line
+		if len(parts) == 2 {
+			message = parts[1]
+		}
+		entries = append(entries, StashEntry{Index: i, Message: message})
+	}
+	return entries, nil
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+func TestStashPop(t *testing.T) {
+	t.Run("pops cleanly", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "stash", "pop"}, "Dropped refs/stash@{0}\n", nil)
+
+		c := NewStashCommands(runner, oscommands.NewCmdObjBuilder())
+		if err := c.Pop(); err != nil {
+			t.Fatalf("Pop() error = %v", err)
+		}
+	})
+
+	t.Run("reports a conflict distinctly instead of swallowing it", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "stash", "pop"},
+			"CONFLICT (content): Merge conflict in main.go\n", errors.New("exit status 1"))
+
+		c := NewStashCommands(runner, oscommands.NewCmdObjBuilder())
+		err := c.Pop()
+		if err == nil {
+			t.Fatal("expected an error when the pop conflicts")
+		}
+		if !strings.Contains(err.Error(), "conflict") {
+			t.Fatalf("expected a conflict-specific error, got %q", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+// WorkingTreeCommands groups git operations that inspect or stage the working tree.
+type WorkingTreeCommands struct {
+	runner  oscommands.Runner
+	builder *oscommands.CmdObjBuilder
+}
+
+func NewWorkingTreeCommands(runner oscommands.Runner, builder *oscommands.CmdObjBuilder) *WorkingTreeCommands {
+	return &WorkingTreeCommands{runner: runner, builder: builder}
+}
+
+func (c *WorkingTreeCommands) AddAll() error {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "add", "."))
+	if err != nil {
+		return fmt.Errorf("failed to add all changes %s", out)
+	}
+	return nil
+}
+
+func (c *WorkingTreeCommands) StatusWithPorcelain() (string, error) {
+	out, err := c.runner.RunWithOutput(c.builder.New("git", "status", "--porcelain"))
+	if err != nil {
+		return "", fmt.Errorf("failed to check working tree status: %s", out)
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewLogger builds gitkick's default logger: a colorized handler for
+// interactive terminals, or a JSON handler (for CI / log processors) when
+// GITKICK_LOG=json is set.
+func NewLogger(out *os.File) *slog.Logger {
+	if os.Getenv("GITKICK_LOG") == "json" {
+		return slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	}
+	return slog.New(NewColorHandler(out, slog.LevelInfo))
+}
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorGray   = "\033[90m"
+)
+
+// ColorHandler is a slog.Handler that prints colorized, single-line records.
+// It keeps the attrs bound via Logger.With and the group path bound via
+// Logger.WithGroup so neither silently vanishes, and drops color codes when
+// writing to something other than a terminal.
+type ColorHandler struct {
+	out     io.Writer
+	mu      *sync.Mutex
+	level   slog.Level
+	noColor bool
+
+	// preformatted holds attrs bound via WithAttrs, already group-qualified.
+	preformatted []slog.Attr
+	groups       []string
+}
+
+func NewColorHandler(out io.Writer, level slog.Level) *ColorHandler {
+	return &ColorHandler{
+		out:     out,
+		mu:      &sync.Mutex{},
+		level:   level,
+		noColor: !isTerminal(out),
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *ColorHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *ColorHandler) Handle(_ context.Context, r slog.Record) error {
+	levelColor := h.color(colorCyan)
+	switch {
+	case r.Level >= slog.LevelError:
+		levelColor = h.color(colorRed)
+	case r.Level >= slog.LevelWarn:
+		levelColor = h.color(colorYellow)
+	case r.Level >= slog.LevelInfo:
+		levelColor = h.color(colorGreen)
+	}
+	reset := h.color(colorReset)
+	gray := h.color(colorGray)
+	cyan := h.color(colorCyan)
+
+	timeStr := r.Time.Format(time.Kitchen)
+
+	msg := fmt.Sprintf("%s%s%s %s%-5s%s %s",
+		gray, timeStr, reset,
+		levelColor, r.Level.String(), reset,
+		r.Message,
+	)
+
+	for _, a := range h.preformatted {
+		msg += fmt.Sprintf(" %s%s%s %v", cyan, a.Key, reset, a.Value)
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		a = h.qualify(a)
+		msg += fmt.Sprintf(" %s%s%s %v", cyan, a.Key, reset, a.Value)
+		return true
+	})
+
+	msg += "\n"
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprint(h.out, msg)
+	return err
+}
+
+// WithAttrs returns a handler that always includes attrs, group-qualified by
+// the current group path, ahead of whatever a call site logs inline.
+func (h *ColorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	qualified := make([]slog.Attr, len(h.preformatted), len(h.preformatted)+len(attrs))
+	copy(qualified, h.preformatted)
+	for _, a := range attrs {
+		qualified = append(qualified, h.qualify(a))
+	}
+
+	return &ColorHandler{
+		out:          h.out,
+		mu:           h.mu,
+		level:        h.level,
+		noColor:      h.noColor,
+		preformatted: qualified,
+		groups:       h.groups,
+	}
+}
+
+// WithGroup returns a handler that nests every subsequent attr's key under name.
+func (h *ColorHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups)+1)
+	copy(groups, h.groups)
+	groups[len(h.groups)] = name
+
+	return &ColorHandler{
+		out:          h.out,
+		mu:           h.mu,
+		level:        h.level,
+		noColor:      h.noColor,
+		preformatted: h.preformatted,
+		groups:       groups,
+	}
+}
+
+func (h *ColorHandler) qualify(a slog.Attr) slog.Attr {
+	if len(h.groups) == 0 {
+		return a
+	}
+	return slog.Attr{Key: strings.Join(h.groups, ".") + "." + a.Key, Value: a.Value}
+}
+
+func (h *ColorHandler) color(code string) string {
+	if h.noColor {
+		return ""
+	}
+	return code
+}
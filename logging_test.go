@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestColorHandlerWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewColorHandler(&buf, slog.LevelInfo)
+	logger := slog.New(handler)
+
+	logger.With("branch", "feat/123-widgets").Info("squash complete")
+
+	out := buf.String()
+	if !strings.Contains(out, "squash complete") {
+		t.Fatalf("expected message in output, got %q", out)
+	}
+	if !strings.Contains(out, "branch") || !strings.Contains(out, "feat/123-widgets") {
+		t.Fatalf("expected bound attr in output, got %q", out)
+	}
+}
+
+func TestColorHandlerWithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewColorHandler(&buf, slog.LevelInfo)
+	logger := slog.New(handler).WithGroup("squash").With("branch", "main")
+
+	logger.Info("done", "diff", 3)
+
+	out := buf.String()
+	if !strings.Contains(out, "squash.branch") {
+		t.Fatalf("expected group-qualified bound attr, got %q", out)
+	}
+	if !strings.Contains(out, "squash.diff") {
+		t.Fatalf("expected group-qualified inline attr, got %q", out)
+	}
+}
+
+func TestColorHandlerNoColorWhenNotATerminal(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewColorHandler(&buf, slog.LevelInfo)
+
+	slog.New(handler).Info("plain")
+
+	if strings.Contains(buf.String(), "\033[") {
+		t.Fatalf("expected no ANSI color codes when writing to a non-terminal, got %q", buf.String())
+	}
+}
@@ -0,0 +1,58 @@
+package oscommands
+
+import "strings"
+
+// CmdObj is a fully-formed command invocation: an argv slice plus the env and
+// working directory it should run with. It is always built from discrete
+// arguments (see CmdObjBuilder.New), never from a shell string, so quoting
+// bugs and shell injection cannot arise.
+type CmdObj struct {
+	args []string
+	env  []string
+	dir  string
+}
+
+func NewCmdObj(args []string) *CmdObj {
+	return &CmdObj{args: args}
+}
+
+func (c *CmdObj) Args() []string {
+	return c.args
+}
+
+// AddEnvVars appends KEY=VALUE pairs to the command's environment.
+func (c *CmdObj) AddEnvVars(vars ...string) *CmdObj {
+	c.env = append(c.env, vars...)
+	return c
+}
+
+func (c *CmdObj) Env() []string {
+	return c.env
+}
+
+// SetDir sets the working directory the command runs in.
+func (c *CmdObj) SetDir(dir string) *CmdObj {
+	c.dir = dir
+	return c
+}
+
+func (c *CmdObj) Dir() string {
+	return c.dir
+}
+
+// ToString renders the argv for logging only; it is never parsed back.
+func (c *CmdObj) ToString() string {
+	return strings.Join(c.args, " ")
+}
+
+// CmdObjBuilder constructs CmdObjs from argv, so call sites never assemble a
+// shell string.
+type CmdObjBuilder struct{}
+
+func NewCmdObjBuilder() *CmdObjBuilder {
+	return &CmdObjBuilder{}
+}
+
+func (b *CmdObjBuilder) New(args ...string) *CmdObj {
+	return NewCmdObj(args)
+}
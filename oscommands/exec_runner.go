@@ -0,0 +1,56 @@
+package oscommands
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+)
+
+// execRunner is the real Runner, backed by os/exec.
+type execRunner struct{}
+
+func NewExecRunner() Runner {
+	return &execRunner{}
+}
+
+func (r *execRunner) build(cmd *CmdObj) *exec.Cmd {
+	c := exec.Command(cmd.args[0], cmd.args[1:]...)
+	if cmd.dir != "" {
+		c.Dir = cmd.dir
+	}
+	if len(cmd.env) > 0 {
+		c.Env = append(os.Environ(), cmd.env...)
+	}
+	return c
+}
+
+func (r *execRunner) Run(cmd *CmdObj) error {
+	_, err := r.RunWithOutput(cmd)
+	return err
+}
+
+func (r *execRunner) RunWithOutput(cmd *CmdObj) (string, error) {
+	out, err := r.build(cmd).CombinedOutput()
+	return string(out), err
+}
+
+func (r *execRunner) RunWithStreaming(cmd *CmdObj, onLine func(line string)) error {
+	c := r.build(cmd)
+
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	c.Stderr = os.Stderr
+
+	if err := c.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		onLine(scanner.Text())
+	}
+
+	return c.Wait()
+}
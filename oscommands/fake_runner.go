@@ -0,0 +1,78 @@
+package oscommands
+
+import "fmt"
+
+// FakeCall is one recorded invocation against a FakeRunner.
+type FakeCall struct {
+	Args []string
+}
+
+// fakeResult is the canned response a FakeRunner returns for a matching argv.
+type fakeResult struct {
+	output string
+	err    error
+}
+
+// FakeRunner is a Runner test double: it records every CmdObj it was asked to
+// run and returns canned output/errors keyed by the joined argv, so tests can
+// assert on the exact git commands a flow issues without a real repo.
+type FakeRunner struct {
+	Calls   []FakeCall
+	results map[string]fakeResult
+}
+
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{results: map[string]fakeResult{}}
+}
+
+// ExpectArgs registers the output/error to return when a command with these
+// exact args is run.
+func (f *FakeRunner) ExpectArgs(args []string, output string, err error) *FakeRunner {
+	f.results[argsKey(args)] = fakeResult{output: output, err: err}
+	return f
+}
+
+func (f *FakeRunner) record(cmd *CmdObj) fakeResult {
+	f.Calls = append(f.Calls, FakeCall{Args: cmd.Args()})
+	return f.results[argsKey(cmd.Args())]
+}
+
+func (f *FakeRunner) Run(cmd *CmdObj) error {
+	res := f.record(cmd)
+	return res.err
+}
+
+func (f *FakeRunner) RunWithOutput(cmd *CmdObj) (string, error) {
+	res := f.record(cmd)
+	return res.output, res.err
+}
+
+func (f *FakeRunner) RunWithStreaming(cmd *CmdObj, onLine func(line string)) error {
+	res := f.record(cmd)
+	for _, line := range splitLines(res.output) {
+		onLine(line)
+	}
+	return res.err
+}
+
+func argsKey(args []string) string {
+	return fmt.Sprint(args)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var lines []string
+	start := 0
+	for i, r := range s {
+		if r == '\n' {
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		lines = append(lines, s[start:])
+	}
+	return lines
+}
@@ -0,0 +1,9 @@
+package oscommands
+
+// Runner executes CmdObjs. execRunner shells out for real; FakeRunner is a
+// test double that records invocations and returns canned results.
+type Runner interface {
+	Run(cmd *CmdObj) error
+	RunWithOutput(cmd *CmdObj) (string, error)
+	RunWithStreaming(cmd *CmdObj, onLine func(line string)) error
+}
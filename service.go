@@ -0,0 +1,321 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/makehlv/gitkick/git"
+)
+
+// BranchLister is the subset of branch operations CleanFallbackBranches needs.
+type BranchLister interface {
+	ListBranchesWithPrefix(prefix string) ([]git.Ref, error)
+	DeleteLocalBranch(ref git.Ref) error
+	DeleteRemoteBranch(ref git.Ref) error
+}
+
+// Committer is the subset of working-tree and commit operations Commit needs.
+type Committer interface {
+	GetCurrentBranch() (git.Ref, error)
+	AddAll() error
+	Commit(message string) error
+}
+
+// Pusher is the subset of remote and status operations Push needs, on top of a Committer.
+type Pusher interface {
+	Committer
+	StatusWithPorcelain() (string, error)
+	Push(branchName string) error
+}
+
+// SquashGit is the subset of branch, commit, and working-tree operations Squash needs.
+type SquashGit interface {
+	GetCurrentBranch() (git.Ref, error)
+	StatusWithPorcelain() (string, error)
+	GetCommitsDiffCount(target git.Ref) (int, error)
+	NewBranch(ref git.Ref) error
+	ResetSoft(commitsFromHead int) error
+	AddAll() error
+	Commit(message string) error
+	GenerateTimestamp() string
+	ListCommitsSince(baseRef string) ([]git.CommitInfo, error)
+	InteractiveRebase(baseRef string, todos []git.TodoEntry) error
+}
+
+// SquashModeSoft resets HEAD~N softly and recommits as one commit, losing
+// per-commit author/date metadata. SquashModeRebase instead drives an
+// interactive rebase that preserves the first commit and squashes the rest
+// onto it.
+const (
+	SquashModeSoft   = ""
+	SquashModeRebase = "rebase"
+)
+
+// Stasher is the subset of git.StashCommands Squash needs to wrap a dirty
+// tree in --autostash mode.
+type Stasher interface {
+	Push(message string) (created bool, err error)
+	Pop() error
+}
+
+// Rebaser is the subset of git.RebaseCommands needed to recover from a
+// rebase that stopped on conflicts.
+type Rebaser interface {
+	Abort() error
+	Continue() error
+}
+
+// CodeFlowManageService drives the higher-level branch/commit/push flows on top of
+// narrow git interfaces, so each flow can be tested with only the operations it uses.
+type CodeFlowManageService struct {
+	logger *slog.Logger
+
+	branches  BranchLister
+	committer Committer
+	pusher    Pusher
+	squash    SquashGit
+	stash     Stasher
+	rebase    Rebaser
+}
+
+func NewCodeFlowManageService(logger *slog.Logger, g *git.GitCommand) *CodeFlowManageService {
+	return &CodeFlowManageService{
+		logger:    logger,
+		branches:  g,
+		committer: g,
+		pusher:    g,
+		squash:    g,
+		stash:     g.Stash,
+		rebase:    g,
+	}
+}
+
+func (s *CodeFlowManageService) AbortRebase() error {
+	if err := s.rebase.Abort(); err != nil {
+		return err
+	}
+	s.logger.Info("Rebase", "status", "aborted")
+	return nil
+}
+
+func (s *CodeFlowManageService) ContinueRebase() error {
+	if err := s.rebase.Continue(); err != nil {
+		return err
+	}
+	s.logger.Info("Rebase", "status", "continued")
+	return nil
+}
+
+// CleanFallbackBranches deletes every local kk-fallback-* branch. When
+// remote is true, it also deletes the matching remote-tracking branch on
+// origin for each one.
+func (s *CodeFlowManageService) CleanFallbackBranches(remote bool) error {
+	branches, err := s.branches.ListBranchesWithPrefix("kk-fallback")
+	if err != nil {
+		return err
+	}
+
+	if len(branches) == 0 {
+		s.logger.Info("Clean", "message", "no fallback branches found")
+		return nil
+	}
+
+	for _, branch := range branches {
+		if err := s.branches.DeleteLocalBranch(branch); err != nil {
+			return err
+		}
+		s.logger.Info("Clean", "deleted", branch.Name)
+
+		if remote {
+			if err := s.branches.DeleteRemoteBranch(branch); err != nil {
+				s.logger.Error("Clean", "failed to delete remote branch", branch.Name, "error", err)
+				continue
+			}
+			s.logger.Info("Clean", "deleted remote", branch.Name)
+		}
+	}
+
+	s.logger.Info("Clean", "total deleted", len(branches))
+	return nil
+}
+
+func (s *CodeFlowManageService) Commit() error {
+	branch, err := s.committer.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	if err := s.committer.AddAll(); err != nil {
+		return err
+	}
+	s.logger.Info("Commit", "status", "staged all changes")
+
+	message := commitMessageFromBranch(branch.Name)
+	if err := s.committer.Commit(message); err != nil {
+		return err
+	}
+	s.logger.Info("Commit", "committed with message", message)
+
+	return nil
+}
+
+func (s *CodeFlowManageService) Push() error {
+	out, err := s.pusher.StatusWithPorcelain()
+	if err != nil {
+		return err
+	}
+	if out != "" {
+		if err := s.Commit(); err != nil {
+			return err
+		}
+	} else {
+		s.logger.Info("Push", "no changes to commit", "skip commit")
+	}
+
+	branch, err := s.pusher.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	if err := s.pusher.Push(branch.Name); err != nil {
+		return err
+	}
+	s.logger.Info("Push", "pushed branch", branch.Name)
+
+	return nil
+}
+
+func (s *CodeFlowManageService) Squash(comparableBranch string, commitMessage string, mode string, autostash bool) error {
+	currentBranch, err := s.squash.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "current branch", currentBranch.Name)
+
+	status, err := s.squash.StatusWithPorcelain()
+	if err != nil {
+		return fmt.Errorf("failed to get working tree status: %s", err)
+	}
+
+	stashed := false
+	var stashName string
+	if strings.TrimSpace(status) != "" {
+		if !autostash {
+			return fmt.Errorf("working tree is not clean: %s", status)
+		}
+
+		stashName = fmt.Sprintf("kk-autostash-%s-%s", currentBranch.Name, s.squash.GenerateTimestamp())
+		stashed, err = s.stash.Push(stashName)
+		if err != nil {
+			return fmt.Errorf("failed to autostash before squash: %w", err)
+		}
+		if stashed {
+			s.logger.Info("Squash", "autostashed as", stashName)
+		}
+	}
+
+	compareRef := git.NewLocalBranchRef(comparableBranch)
+	if err := s.squashAndCommit(currentBranch, compareRef, commitMessage, mode); err != nil {
+		if stashed {
+			s.logger.Error("Squash", "failed with a stash still pending", fmt.Sprintf("run `git stash pop` to restore %s", stashName))
+		}
+		return err
+	}
+
+	if stashed {
+		if err := s.stash.Pop(); err != nil {
+			return err
+		}
+		s.logger.Info("Squash", "popped autostash", "restored working tree changes")
+	}
+
+	return nil
+}
+
+// squashAndCommit runs the compare/reset-or-rebase/recommit steps of Squash.
+// It is kept separate from Squash so the autostash pop only runs once this
+// has fully succeeded.
+func (s *CodeFlowManageService) squashAndCommit(currentBranch, comparableBranch git.Ref, commitMessage, mode string) error {
+	if comparableBranch.Name == currentBranch.Name {
+		return fmt.Errorf("comparable branch is the same as current branch")
+	}
+
+	diff, err := s.squash.GetCommitsDiffCount(comparableBranch)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "diff count", diff, "between", currentBranch.Name, "and", comparableBranch.Name)
+
+	if diff <= 1 {
+		s.logger.Info("Squash", "diff <= 1", "nothing to squash")
+		return nil
+	}
+
+	ts := s.squash.GenerateTimestamp()
+	fallbackBranch := git.NewLocalBranchRef(fmt.Sprintf("%s-%s-%s", "kk-fallback", currentBranch.Name, ts))
+	if err := s.squash.NewBranch(fallbackBranch); err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "fallback branch", fallbackBranch.Name)
+
+	if mode == SquashModeRebase {
+		return s.squashViaRebase(currentBranch, comparableBranch, commitMessage)
+	}
+
+	if err := s.squash.ResetSoft(diff); err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "commits reset", diff, "on branch", currentBranch.Name)
+
+	if err := s.squash.AddAll(); err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "add all changes on branch", currentBranch.Name)
+
+	message := commitMessage
+	if message == "" {
+		message = commitMessageFromBranch(currentBranch.Name)
+	}
+	if err := s.squash.Commit(message); err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "squash committed as", message, "on branch", currentBranch.Name)
+
+	return nil
+}
+
+// squashViaRebase drives a non-interactive `git rebase -i` against
+// comparableBranch with a pre-written todo list: the oldest commit is kept as
+// a pick, every later commit is squashed into it, preserving the first
+// commit's author/date.
+func (s *CodeFlowManageService) squashViaRebase(currentBranch, comparableBranch git.Ref, commitMessage string) error {
+	commits, err := s.squash.ListCommitsSince(comparableBranch.Name)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		s.logger.Info("Squash", "no commits found", "nothing to rebase")
+		return nil
+	}
+
+	todos := make([]git.TodoEntry, 0, len(commits))
+	for i, commit := range commits {
+		action := git.TodoActionSquash
+		subject := commit.Subject
+		if i == 0 {
+			action = git.TodoActionPick
+			if commitMessage != "" {
+				subject = commitMessage
+			}
+		}
+		todos = append(todos, git.TodoEntry{Action: action, SHA: commit.SHA, Subject: subject})
+	}
+
+	if err := s.squash.InteractiveRebase(comparableBranch.Name, todos); err != nil {
+		return err
+	}
+	s.logger.Info("Squash", "rebased and squashed onto", comparableBranch.Name, "commits", len(commits))
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/makehlv/gitkick/git"
+	"github.com/makehlv/gitkick/oscommands"
+)
+
+func newTestService(runner oscommands.Runner) *CodeFlowManageService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewCodeFlowManageService(logger, git.NewGitCommandWithRunner(runner))
+}
+
+func TestCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		wantMsg string
+	}{
+		{name: "ticket branch", branch: "feat/123-add-widgets", wantMsg: "[feat-123] add widgets"},
+		{name: "unstructured branch", branch: "scratch", wantMsg: "scratch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := oscommands.NewFakeRunner()
+			runner.ExpectArgs([]string{"git", "branch", "--show-current"}, tt.branch+"\n", nil)
+
+			service := newTestService(runner)
+			if err := service.Commit(); err != nil {
+				t.Fatalf("Commit() error = %v", err)
+			}
+
+			wantCommit := []string{"git", "commit", "-m", tt.wantMsg}
+			assertCalled(t, runner, []string{"git", "add", "."})
+			assertCalled(t, runner, wantCommit)
+		})
+	}
+}
+
+func TestPush(t *testing.T) {
+	t.Run("pushes a clean tree without committing", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, "", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "main\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Push(); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+
+		assertCalled(t, runner, []string{"git", "push", "--set-upstream", "origin", "main"})
+		assertNotCalled(t, runner, []string{"git", "add", "."})
+	})
+
+	t.Run("commits a dirty tree before pushing", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, " M main.go\n", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "main\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Push(); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+
+		assertCalled(t, runner, []string{"git", "add", "."})
+		assertCalled(t, runner, []string{"git", "push", "--set-upstream", "origin", "main"})
+	})
+}
+
+func TestCleanFallbackBranches(t *testing.T) {
+	t.Run("deletes every fallback branch", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "branch", "--list", "kk-fallback*"}, "  kk-fallback-main-1\n  kk-fallback-main-2\n", nil)
+
+		service := newTestService(runner)
+		if err := service.CleanFallbackBranches(false); err != nil {
+			t.Fatalf("CleanFallbackBranches() error = %v", err)
+		}
+
+		assertCalled(t, runner, []string{"git", "branch", "-D", "kk-fallback-main-1"})
+		assertCalled(t, runner, []string{"git", "branch", "-D", "kk-fallback-main-2"})
+		assertNotCalled(t, runner, []string{"git", "push", "origin", "--delete", "kk-fallback-main-1"})
+	})
+
+	t.Run("does nothing when there are no fallback branches", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "branch", "--list", "kk-fallback*"}, "", nil)
+
+		service := newTestService(runner)
+		if err := service.CleanFallbackBranches(false); err != nil {
+			t.Fatalf("CleanFallbackBranches() error = %v", err)
+		}
+
+		if len(runner.Calls) != 1 {
+			t.Fatalf("expected only the list call, got %v", runner.Calls)
+		}
+	})
+
+	t.Run("also deletes remote-tracking branches with --remote", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "branch", "--list", "kk-fallback*"}, "  kk-fallback-main-1\n", nil)
+
+		service := newTestService(runner)
+		if err := service.CleanFallbackBranches(true); err != nil {
+			t.Fatalf("CleanFallbackBranches() error = %v", err)
+		}
+
+		assertCalled(t, runner, []string{"git", "branch", "-D", "kk-fallback-main-1"})
+		assertCalled(t, runner, []string{"git", "push", "origin", "--delete", "kk-fallback-main-1"})
+	})
+}
+
+func TestSquash(t *testing.T) {
+	t.Run("refuses a dirty tree", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, " M main.go\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, false); err == nil {
+			t.Fatal("expected an error for a dirty tree")
+		}
+	})
+
+	t.Run("does nothing when there is at most one commit to squash", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, "", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "feat/123-widgets\n", nil)
+		runner.ExpectArgs([]string{"git", "cherry", "-v", "develop"}, "", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, false); err != nil {
+			t.Fatalf("Squash() error = %v", err)
+		}
+
+		assertNotCalled(t, runner, []string{"git", "reset", "--soft", "HEAD~0"})
+	})
+
+	t.Run("errors when the comparable branch is the current branch", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, "", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "develop\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, false); err == nil {
+			t.Fatal("expected an error when comparing a branch to itself")
+		}
+	})
+
+	t.Run("resets and recommits when there is history to squash", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, "", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "feat/123-widgets\n", nil)
+		runner.ExpectArgs([]string{"git", "cherry", "-v", "develop"}, "+ abc\n+ def\n+ ghi\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "custom message", SquashModeSoft, false); err != nil {
+			t.Fatalf("Squash() error = %v", err)
+		}
+
+		assertCalled(t, runner, []string{"git", "reset", "--soft", "HEAD~3"})
+		assertCalled(t, runner, []string{"git", "add", "."})
+		assertCalled(t, runner, []string{"git", "commit", "-m", "custom message"})
+	})
+
+	t.Run("surfaces a failure to reset", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, "", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "feat/123-widgets\n", nil)
+		runner.ExpectArgs([]string{"git", "cherry", "-v", "develop"}, "+ abc\n+ def\n", nil)
+		runner.ExpectArgs([]string{"git", "reset", "--soft", "HEAD~2"}, "", errors.New("reset failed"))
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, false); err == nil {
+			t.Fatal("expected the reset failure to propagate")
+		}
+	})
+
+	t.Run("autostashes a dirty tree and pops on success", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, " M main.go\n", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "feat/123-widgets\n", nil)
+		runner.ExpectArgs([]string{"git", "cherry", "-v", "develop"}, "+ abc\n+ def\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, true); err != nil {
+			t.Fatalf("Squash() error = %v", err)
+		}
+
+		assertCalledPrefix(t, runner, []string{"git", "stash", "push", "--include-untracked", "-m"})
+		assertCalled(t, runner, []string{"git", "stash", "pop"})
+	})
+
+	t.Run("refuses a dirty tree without --autostash", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, " M main.go\n", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "feat/123-widgets\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, false); err == nil {
+			t.Fatal("expected an error for a dirty tree")
+		}
+
+		assertNotCalled(t, runner, []string{"git", "stash", "pop"})
+	})
+
+	t.Run("leaves the stash in place when the squash fails", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, " M main.go\n", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "develop\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeSoft, true); err == nil {
+			t.Fatal("expected an error when comparing a branch to itself")
+		}
+
+		assertNotCalled(t, runner, []string{"git", "stash", "pop"})
+	})
+
+	t.Run("rebases instead of resetting in rebase mode", func(t *testing.T) {
+		runner := oscommands.NewFakeRunner()
+		runner.ExpectArgs([]string{"git", "status", "--porcelain"}, "", nil)
+		runner.ExpectArgs([]string{"git", "branch", "--show-current"}, "feat/123-widgets\n", nil)
+		runner.ExpectArgs([]string{"git", "cherry", "-v", "develop"}, "+ abc\n+ def\n", nil)
+		runner.ExpectArgs([]string{"git", "log", "--reverse", "--format=%h\t%s", "develop..HEAD"}, "abc123\tfirst commit\ndef456\tsecond commit\n", nil)
+
+		service := newTestService(runner)
+		if err := service.Squash("develop", "", SquashModeRebase, false); err != nil {
+			t.Fatalf("Squash() error = %v", err)
+		}
+
+		assertCalled(t, runner, []string{"git", "rebase", "-i", "develop"})
+		assertNotCalled(t, runner, []string{"git", "reset", "--soft", "HEAD~2"})
+	})
+}
+
+func assertCalled(t *testing.T, runner *oscommands.FakeRunner, args []string) {
+	t.Helper()
+	for _, call := range runner.Calls {
+		if reflect.DeepEqual(call.Args, args) {
+			return
+		}
+	}
+	t.Fatalf("expected a call with args %v, got %v", args, runner.Calls)
+}
+
+func assertCalledPrefix(t *testing.T, runner *oscommands.FakeRunner, prefix []string) {
+	t.Helper()
+	for _, call := range runner.Calls {
+		if len(call.Args) >= len(prefix) && reflect.DeepEqual(call.Args[:len(prefix)], prefix) {
+			return
+		}
+	}
+	t.Fatalf("expected a call starting with %v, got %v", prefix, runner.Calls)
+}
+
+func assertNotCalled(t *testing.T, runner *oscommands.FakeRunner, args []string) {
+	t.Helper()
+	for _, call := range runner.Calls {
+		if reflect.DeepEqual(call.Args, args) {
+			t.Fatalf("did not expect a call with args %v, got %v", args, runner.Calls)
+		}
+	}
+}
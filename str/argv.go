@@ -0,0 +1,60 @@
+package str
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToArgv tokenizes a command string into argv, honouring single and double
+// quotes and backslash escapes, without ever invoking a shell. Custom
+// commands are built from the resulting slice, not from the raw string, so
+// shell injection cannot arise.
+func ToArgv(s string) ([]string, error) {
+	var argv []string
+	var current strings.Builder
+	hasToken := false
+
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+			hasToken = true
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			hasToken = true
+		case r == ' ' || r == '\t':
+			if hasToken {
+				argv = append(argv, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command: %s", quote, s)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in command: %s", s)
+	}
+	if hasToken {
+		argv = append(argv, current.String())
+	}
+
+	return argv, nil
+}
@@ -0,0 +1,40 @@
+package str
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToArgv(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "simple", in: "git status", want: []string{"git", "status"}},
+		{name: "double quoted arg with spaces", in: `git commit -m "fix the thing"`, want: []string{"git", "commit", "-m", "fix the thing"}},
+		{name: "single quoted arg", in: `echo 'hello world'`, want: []string{"echo", "hello world"}},
+		{name: "escaped space outside quotes", in: `echo foo\ bar`, want: []string{"echo", "foo bar"}},
+		{name: "extra whitespace collapses", in: "  git   status  ", want: []string{"git", "status"}},
+		{name: "unterminated quote errors", in: `git commit -m "oops`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToArgv(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ToArgv(%q) expected an error", tt.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToArgv(%q) unexpected error: %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ToArgv(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}